@@ -0,0 +1,134 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	"sync"
+)
+
+// Category classifies the general kind of failure a Code represents. It is
+// deliberately coarse - scope and detail carry the caller-specific
+// information.
+type Category uint32
+
+// The set of categories known to this package. Callers are free to use
+// values outside this range for their own purposes; CodeOf and Message
+// treat any uint32 as a valid category.
+const (
+	CategoryUnspecified Category = iota
+	CategoryValidation
+	CategoryDatabase
+	CategoryResource
+	CategoryGRPC
+	CategoryAuth
+	CategorySystem
+	CategoryPubSub
+)
+
+// Code is a structured, numeric description of an error: which service or
+// module raised it (Scope), what kind of failure it was (Category), and a
+// caller-defined Detail identifying the specific condition within that
+// category.
+type Code struct {
+	Scope    uint32
+	Category Category
+	Detail   uint32
+}
+
+// Coder is implemented by errors that carry a Code. *Err implements Coder,
+// so any error built with the constructors in this package can carry a
+// code.
+type Coder interface {
+	Code() (code Code, ok bool)
+}
+
+// Code is part of the Coder interface. It reports the code attached to e
+// directly, if any; it does not look further down the annotation stack.
+// Use CodeOf for that.
+func (e *Err) Code() (code Code, ok bool) {
+	if e.code == nil {
+		return Code{}, false
+	}
+	return *e.code, true
+}
+
+// WithCode attaches the given code to err, wrapping it in the same way as
+// Trace. The resulting error's Cause and previous error are unaffected, so
+// the code rides alongside the rest of the annotation stack.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.WithCode(err, scopeBilling, errors.CategoryDatabase, detailConnRefused)
+//	}
+func WithCode(err error, scope uint32, category Category, detail uint32) error {
+	if err == nil {
+		return nil
+	}
+	code := Code{Scope: scope, Category: category, Detail: detail}
+	e := &Err{
+		message:  err.Error(),
+		previous: err,
+		cause:    Cause(err),
+		code:     &code,
+	}
+	e.SetLocation(1)
+	return e
+}
+
+// CodeOf walks the annotation stack of err from the outside in - the same
+// direction ErrorStack prints it in reverse - and returns the first
+// attached Code it finds. If err was decorated with WithCode more than
+// once, this is the outermost (most recently attached) code, the same way
+// errors.As returns the first match it finds walking outward in. It
+// reports ok=false if no error in the stack carries a code.
+func CodeOf(err error) (scope, category, detail uint32, ok bool) {
+	for err != nil {
+		if coder, isCoder := err.(Coder); isCoder {
+			if code, has := coder.Code(); has {
+				return code.Scope, uint32(code.Category), code.Detail, true
+			}
+		}
+		w, isWrapper := err.(wrapper)
+		if !isWrapper {
+			break
+		}
+		err = w.Underlying()
+	}
+	return 0, 0, 0, false
+}
+
+// messageKey identifies a registered canonical message.
+type messageKey struct {
+	category Category
+	detail   uint32
+}
+
+var messageRegistry = struct {
+	mu sync.RWMutex
+	m  map[messageKey]string
+}{m: make(map[messageKey]string)}
+
+// RegisterMessage records the canonical message template for the given
+// (category, detail) pair, analogous to the well known text for an HTTP
+// status code. Re-registering the same pair overwrites the previous
+// template.
+func RegisterMessage(category Category, detail uint32, template string) {
+	messageRegistry.mu.Lock()
+	defer messageRegistry.mu.Unlock()
+	messageRegistry.m[messageKey{category, detail}] = template
+}
+
+// Message returns the canonical message registered for err's code via
+// RegisterMessage, or "" if err has no code or no template was registered
+// for it.
+func Message(err error) string {
+	_, category, detail, ok := CodeOf(err)
+	if !ok {
+		return ""
+	}
+	messageRegistry.mu.RLock()
+	defer messageRegistry.mu.RUnlock()
+	return messageRegistry.m[messageKey{Category(category), detail}]
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type codeSuite struct{}
+
+var _ = gc.Suite(&codeSuite{})
+
+func (*codeSuite) TestWithCodeAndCodeOf(c *gc.C) {
+	err := errors.WithCode(errors.New("boom"), 7, errors.CategoryDatabase, 42)
+
+	scope, category, detail, ok := errors.CodeOf(err)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(scope, gc.Equals, uint32(7))
+	c.Assert(category, gc.Equals, uint32(errors.CategoryDatabase))
+	c.Assert(detail, gc.Equals, uint32(42))
+
+	_, _, _, ok = errors.CodeOf(errors.New("no code"))
+	c.Assert(ok, gc.Equals, false)
+}
+
+func (*codeSuite) TestCodeSurvivesDecoration(c *gc.C) {
+	err := errors.WithCode(errors.New("boom"), 1, errors.CategoryAuth, 2)
+	err = errors.Trace(err)
+	err = errors.Annotate(err, "context")
+	err = errors.Wrap(err, errors.New("wrapped"))
+	err = errors.Mask(err)
+
+	scope, category, detail, ok := errors.CodeOf(err)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(scope, gc.Equals, uint32(1))
+	c.Assert(category, gc.Equals, uint32(errors.CategoryAuth))
+	c.Assert(detail, gc.Equals, uint32(2))
+}
+
+func (*codeSuite) TestCodeOfPrefersOutermostCode(c *gc.C) {
+	err := errors.WithCode(errors.New("boom"), 1, errors.CategoryAuth, 2)
+	err = errors.Annotate(err, "context")
+	err = errors.WithCode(err, 9, errors.CategorySystem, 9)
+
+	scope, category, detail, ok := errors.CodeOf(err)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(scope, gc.Equals, uint32(9))
+	c.Assert(category, gc.Equals, uint32(errors.CategorySystem))
+	c.Assert(detail, gc.Equals, uint32(9))
+}
+
+func (*codeSuite) TestMessage(c *gc.C) {
+	errors.RegisterMessage(errors.CategoryValidation, 99, "field is required")
+
+	err := errors.WithCode(errors.New("boom"), 0, errors.CategoryValidation, 99)
+	c.Assert(errors.Message(err), gc.Equals, "field is required")
+
+	unregistered := errors.WithCode(errors.New("boom"), 0, errors.CategoryValidation, 100)
+	c.Assert(errors.Message(unregistered), gc.Equals, "")
+
+	c.Assert(errors.Message(errors.New("no code")), gc.Equals, "")
+}
+
+func (*codeSuite) TestHTTPAndGRPCStatusFromTypedError(c *gc.C) {
+	err := errors.NotFoundf("widget")
+	c.Assert(errors.HTTPStatus(err), gc.Equals, http.StatusNotFound)
+	c.Assert(errors.GRPCStatus(err).Code(), gc.Equals, codes.NotFound)
+
+	// Decorating the error should not change the resolved status.
+	traced := errors.Trace(err)
+	c.Assert(errors.HTTPStatus(traced), gc.Equals, http.StatusNotFound)
+	c.Assert(errors.GRPCStatus(traced).Code(), gc.Equals, codes.NotFound)
+}
+
+func (*codeSuite) TestHTTPAndGRPCStatusFromCodeTakesPriority(c *gc.C) {
+	err := errors.WithCode(errors.NotFoundf("widget"), 0, errors.CategoryAuth, 0)
+	c.Assert(errors.HTTPStatus(err), gc.Equals, http.StatusUnauthorized)
+	c.Assert(errors.GRPCStatus(err).Code(), gc.Equals, codes.Unauthenticated)
+}
+
+func (*codeSuite) TestHTTPAndGRPCStatusDefault(c *gc.C) {
+	err := errors.New("mystery failure")
+	c.Assert(errors.HTTPStatus(err), gc.Equals, http.StatusInternalServerError)
+	c.Assert(errors.GRPCStatus(err).Code(), gc.Equals, codes.Unknown)
+}
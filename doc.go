@@ -0,0 +1,13 @@
+// Copyright 2013, 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package errors provides an easy way to annotate errors without losing the
+// original error context.
+//
+// The exported New and Errorf functions are drop-in replacements for the
+// functions of the same name in the standard "errors" and "fmt" packages
+// respectively. Wherever an error is created or received, wrapping it with
+// Trace, Annotate or Mask (as appropriate) preserves the original error
+// location, while Cause still lets callers reach through to the underlying
+// error for comparisons against sentinel values.
+package errors
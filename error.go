@@ -0,0 +1,261 @@
+// Copyright 2013, 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Err holds a description of an error along with information about where
+// the error was created and what (if anything) caused it.
+//
+// Ideally, error types should not be used as values, but rather created
+// through the package level functions (New, Annotate, Trace, and so on) so
+// that the location and previous-error information is always filled in.
+type Err struct {
+	// message is the error message as returned by Error().
+	message string
+
+	// cause holds the error that is returned by the Cause method, or nil
+	// if the cause is this error itself (see the Cause function below).
+	cause error
+
+	// previous holds the error that was passed in to whichever function
+	// created this error, and is used to build up the full error stack.
+	previous error
+
+	// pc holds the program counter of the call that created this error,
+	// recorded by SetLocation under ModeLazy. file and line are resolved
+	// from pc on first access (ModeLazy), or recorded directly by
+	// SetLocation (ModeEager).
+	pc   uintptr
+	file string
+	line int
+
+	// loc holds a *resolvedLocation once Location has resolved pc under
+	// ModeLazy, or nil until then. It is accessed with the atomic
+	// package so concurrent calls to Location - the common case for an
+	// error that is logged and also returned or retried - don't race on
+	// file and line the way writing them directly would.
+	loc unsafe.Pointer
+
+	// code holds the structured code attached via WithCode, if any.
+	code *Code
+
+	// typeTag holds the tag one of errortypes.go's typed-error
+	// constructors recorded, if this Err was built by one of them, or ""
+	// otherwise. It is recorded on Err itself, rather than derived from
+	// the concrete type embedding it, so it is still readable from
+	// methods promoted to that outer type.
+	typeTag string
+}
+
+// resolvedLocation is what loc points to once Location has resolved an
+// Err's pc into a file and line.
+type resolvedLocation struct {
+	file string
+	line int
+}
+
+// Locationer can be implemented by any error type that wants to expose the
+// file and line of where the error was created.
+type Locationer interface {
+	Location() (file string, line int)
+}
+
+// locationSetter is implemented by errors that can record the location of
+// the call that created or decorated them.
+type locationSetter interface {
+	SetLocation(callDepth int)
+}
+
+// causer is implemented by any error that can expose the error that it
+// wraps.
+type causer interface {
+	Cause() error
+}
+
+// wrapper is implemented by any error that can expose the previous error in
+// the annotation stack.
+type wrapper interface {
+	Underlying() error
+}
+
+// NewErr is used to return an Err for the purpose of embedding in other
+// structures. The location is not specified, and needs to be set using a
+// call to SetLocation.
+//
+// For example:
+//
+//	type FooError struct {
+//	    errors.Err
+//	    code int
+//	}
+//
+//	func NewFooError(code int) error {
+//	    err := &FooError{errors.NewErr("foo"), code}
+//	    err.SetLocation(1)
+//	    return err
+//	}
+func NewErr(format string, args ...interface{}) Err {
+	return Err{message: fmt.Sprintf(format, args...)}
+}
+
+// NewErrWithCause is used to return an Err with cause by the names of the
+// recognized errors in this package, for the purpose of embedding in other
+// structures. The location is not specified, and needs to be set using a
+// call to SetLocation.
+//
+// For example:
+//
+//	type FooError struct {
+//	    errors.Err
+//	    code int
+//	}
+//
+//	func NewFooError(code int) error {
+//	    err := &FooError{errors.NewErrWithCause(someErr, "foo"), code}
+//	    err.SetLocation(1)
+//	    return err
+//	}
+func NewErrWithCause(other error, format string, args ...interface{}) Err {
+	return Err{message: fmt.Sprintf(format, args...), cause: Cause(other)}
+}
+
+// Location is part of the Locationer interface. Under ModeLazy, the first
+// call resolves file and line from the recorded program counter and
+// caches the result for later calls, racily but safely if several
+// goroutines resolve it at once; under ModeOff, or when built with the
+// juju_errors_nolocation tag, it always returns "", 0.
+func (e *Err) Location() (file string, line int) {
+	if p := atomic.LoadPointer(&e.loc); p != nil {
+		rl := (*resolvedLocation)(p)
+		return rl.file, rl.line
+	}
+	if e.file != "" || e.pc == 0 {
+		return e.file, e.line
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	rl := &resolvedLocation{file: frame.File, line: frame.Line}
+	atomic.CompareAndSwapPointer(&e.loc, nil, unsafe.Pointer(rl))
+
+	// Use whichever *resolvedLocation won the race, ours or another
+	// goroutine's, so every caller sees the same answer from here on.
+	rl = (*resolvedLocation)(atomic.LoadPointer(&e.loc))
+	return rl.file, rl.line
+}
+
+// SetLocation records the location of the call callDepth levels above the
+// call to SetLocation itself. Callers that decorate Err through embedding
+// should call this directly after construction.
+//
+// What exactly gets recorded depends on the current stack mode (see
+// SetStackMode): under ModeEager the file and line are resolved
+// immediately; under ModeLazy (the default) only a program counter is
+// stored, and the file and line are resolved lazily by Location. Under
+// ModeOff, or when built with the juju_errors_nolocation tag, this is a
+// no-op.
+func (e *Err) SetLocation(callDepth int) {
+	if !locationRecordingEnabled {
+		return
+	}
+	switch stackMode() {
+	case ModeOff:
+	case ModeEager:
+		_, file, line, _ := runtime.Caller(callDepth + 1)
+		e.file = file
+		e.line = line
+	default: // ModeLazy
+		var pcs [1]uintptr
+		if runtime.Callers(callDepth+2, pcs[:]) > 0 {
+			e.pc = pcs[0]
+		}
+	}
+}
+
+// localMessage returns e's own message, with the previous error's text
+// trimmed from the end if present. This is the per-frame text used by
+// ErrorStack and MarshalJSON; for a frame that contributed no text of
+// its own, such as one created by Trace or Mask, it is "".
+func (e *Err) localMessage() string {
+	message := e.message
+	if e.previous != nil {
+		if prev := e.previous.Error(); prev != "" && strings.HasSuffix(message, prev) {
+			message = strings.TrimSuffix(message, prev)
+			message = strings.TrimSuffix(message, ": ")
+		}
+	}
+	return message
+}
+
+// rawTypeTag returns the typed-error tag recorded on e by one of
+// errortypes.go's constructors, or "" if e is a plain Err. Unlike a type
+// switch on err's concrete type, this works correctly even when called
+// through a method promoted from an outer type that embeds Err.
+func (e *Err) rawTypeTag() string {
+	return e.typeTag
+}
+
+// causeSevered reports whether e's Cause was deliberately cut off from
+// its previous error, the way Mask and Maskf do, rather than propagated
+// through it the way Annotate, Trace and WithCode do.
+func (e *Err) causeSevered() bool {
+	return e.cause == nil && e.previous != nil
+}
+
+// Cause is part of the causer interface.
+func (e *Err) Cause() error {
+	return e.cause
+}
+
+// Underlying is part of the wrapper interface. It returns the previous
+// error in the annotation stack, or nil if this is the first error in the
+// stack.
+func (e *Err) Underlying() error {
+	return e.previous
+}
+
+// Unwrap allows the standard library "errors" package (and hence errors.Is
+// and errors.As) to traverse the annotation stack.
+func (e *Err) Unwrap() error {
+	return e.previous
+}
+
+// StackTrace returns one line per error in the annotation stack, in the
+// same format as ErrorStack.
+func (e *Err) StackTrace() []string {
+	return errorStack(e)
+}
+
+// Error implements error.Error.
+func (e *Err) Error() string {
+	return e.message
+}
+
+// Format implements fmt.Formatter. The following verbs are supported:
+//
+//	%v, %s    print the message only
+//	%q        print the message, quoted
+//	%+v       print the full error stack, as returned by ErrorStack
+func (e *Err) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprint(s, ErrorStack(e))
+			return
+		}
+		fallthrough
+	case 's':
+		fmt.Fprint(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, e, e.Error())
+	}
+}
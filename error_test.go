@@ -0,0 +1,117 @@
+// Copyright 2013, 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+func Test(t *testing.T) {
+	gc.TestingT(t)
+}
+
+// someErr is a plain *errors.Err used by tests that just need an arbitrary
+// error value to decorate.
+var someErr = errors.New("some error")
+
+// errorLocationValue returns the file:line of the statement immediately
+// above its own call site. Tests call it on the line directly following
+// the call that is expected to have recorded that location.
+func errorLocationValue(c *gc.C) string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", file, line-1)
+}
+
+// simpleError is a comparable error type, distinct from *errors.Err, used
+// to exercise Wrap with a non-juju cause.
+type simpleError struct {
+	message string
+}
+
+func newError(message string) error {
+	return &simpleError{message}
+}
+
+func (e *simpleError) Error() string {
+	return e.message
+}
+
+// nonComparableError contains a slice field, so values of this type cannot
+// be compared with ==. This exercises the code paths that must not rely on
+// error identity.
+type nonComparableError struct {
+	message string
+	ignored []string
+}
+
+func newNonComparableError(message string) error {
+	return nonComparableError{message: message}
+}
+
+func (e nonComparableError) Error() string {
+	return e.message
+}
+
+// containsChecker checks that a string contains a substring.
+type containsChecker struct {
+	*gc.CheckerInfo
+}
+
+// Contains is a gocheck checker asserting that the obtained string contains
+// the expected substring.
+var Contains gc.Checker = &containsChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "Contains", Params: []string{"obtained", "substring"}},
+}
+
+func (checker *containsChecker) Check(params []interface{}, names []string) (result bool, errStr string) {
+	value, ok := params[0].(string)
+	if !ok {
+		return false, "obtained value is not a string"
+	}
+	substring, ok := params[1].(string)
+	if !ok {
+		return false, "substring must be a string"
+	}
+	return strings.Contains(value, substring), ""
+}
+
+// satisfiesChecker checks that a value satisfies a func(T) bool predicate.
+type satisfiesChecker struct {
+	*gc.CheckerInfo
+}
+
+// Satisfies is a gocheck checker asserting that the given predicate
+// function, when called with the obtained value, returns true.
+var Satisfies gc.Checker = &satisfiesChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "Satisfies", Params: []string{"obtained", "func(T) bool"}},
+}
+
+func (checker *satisfiesChecker) Check(params []interface{}, names []string) (result bool, errStr string) {
+	f := reflect.ValueOf(params[1])
+	ftype := f.Type()
+	if ftype.Kind() != reflect.Func || ftype.NumIn() != 1 || ftype.NumOut() != 1 {
+		return false, "expected func(T) bool"
+	}
+
+	var arg reflect.Value
+	if params[0] == nil {
+		arg = reflect.Zero(ftype.In(0))
+	} else {
+		arg = reflect.ValueOf(params[0])
+	}
+	if !arg.Type().AssignableTo(ftype.In(0)) {
+		return false, fmt.Sprintf("%T is not assignable to %v", params[0], ftype.In(0))
+	}
+
+	result = f.Call([]reflect.Value{arg})[0].Bool()
+	return result, ""
+}
@@ -0,0 +1,517 @@
+// Copyright 2013, 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+// wrapMessage builds the Error() text for a typed error given the error it
+// wraps (which may be nil) and a message (which may be empty).
+func wrapMessage(cause error, msg string) string {
+	switch {
+	case msg == "" && cause == nil:
+		return ""
+	case msg == "":
+		return cause.Error()
+	case cause == nil:
+		return msg
+	default:
+		return msg + ": " + cause.Error()
+	}
+}
+
+// notFound represents an error when something has not been found.
+type notFound struct {
+	Err
+}
+
+// NotFoundf returns an error which satisfies IsNotFound().
+func NotFoundf(format string, args ...interface{}) error {
+	err := &notFound{Err: NewErr(format+" not found", args...)}
+	err.typeTag = "notFound"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotFound returns an error which wraps err, has the given message and
+// satisfies IsNotFound().
+func NewNotFound(cause error, msg string) error {
+	err := &notFound{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notFound"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotFound reports whether err was created with NotFoundf() or
+// NewNotFound().
+func IsNotFound(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotFound); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notFound)
+	return ok
+}
+
+// userNotFound represents an error when a non-existent user is looked up.
+type userNotFound struct {
+	Err
+}
+
+// UserNotFoundf returns an error which satisfies IsUserNotFound().
+func UserNotFoundf(format string, args ...interface{}) error {
+	err := &userNotFound{Err: NewErr(format+" user not found", args...)}
+	err.typeTag = "userNotFound"
+	err.SetLocation(1)
+	return err
+}
+
+// NewUserNotFound returns an error which wraps err, has the given message
+// and satisfies IsUserNotFound().
+func NewUserNotFound(cause error, msg string) error {
+	err := &userNotFound{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "userNotFound"
+	err.SetLocation(1)
+	return err
+}
+
+// IsUserNotFound reports whether err was created with UserNotFoundf() or
+// NewUserNotFound().
+func IsUserNotFound(err error) bool {
+	if all, isMulti := isAllMulti(err, IsUserNotFound); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*userNotFound)
+	return ok
+}
+
+// unauthorized represents an error when an operation is unauthorized.
+type unauthorized struct {
+	Err
+}
+
+// Unauthorizedf returns an error which satisfies IsUnauthorized().
+func Unauthorizedf(format string, args ...interface{}) error {
+	err := &unauthorized{Err: NewErr(format, args...)}
+	err.typeTag = "unauthorized"
+	err.SetLocation(1)
+	return err
+}
+
+// NewUnauthorized returns an error which wraps err, has the given message
+// and satisfies IsUnauthorized().
+func NewUnauthorized(cause error, msg string) error {
+	err := &unauthorized{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "unauthorized"
+	err.SetLocation(1)
+	return err
+}
+
+// IsUnauthorized reports whether err was created with Unauthorizedf() or
+// NewUnauthorized().
+func IsUnauthorized(err error) bool {
+	if all, isMulti := isAllMulti(err, IsUnauthorized); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*unauthorized)
+	return ok
+}
+
+// notImplemented represents an error when something is not implemented.
+type notImplemented struct {
+	Err
+}
+
+// NotImplementedf returns an error which satisfies IsNotImplemented().
+func NotImplementedf(format string, args ...interface{}) error {
+	err := &notImplemented{Err: NewErr(format+" not implemented", args...)}
+	err.typeTag = "notImplemented"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotImplemented returns an error which wraps err, has the given message
+// and satisfies IsNotImplemented().
+func NewNotImplemented(cause error, msg string) error {
+	err := &notImplemented{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notImplemented"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotImplemented reports whether err was created with NotImplementedf()
+// or NewNotImplemented().
+func IsNotImplemented(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotImplemented); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notImplemented)
+	return ok
+}
+
+// alreadyExists represents an error when something already exists.
+type alreadyExists struct {
+	Err
+}
+
+// AlreadyExistsf returns an error which satisfies IsAlreadyExists().
+func AlreadyExistsf(format string, args ...interface{}) error {
+	err := &alreadyExists{Err: NewErr(format+" already exists", args...)}
+	err.typeTag = "alreadyExists"
+	err.SetLocation(1)
+	return err
+}
+
+// NewAlreadyExists returns an error which wraps err, has the given message
+// and satisfies IsAlreadyExists().
+func NewAlreadyExists(cause error, msg string) error {
+	err := &alreadyExists{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "alreadyExists"
+	err.SetLocation(1)
+	return err
+}
+
+// IsAlreadyExists reports whether err was created with AlreadyExistsf() or
+// NewAlreadyExists().
+func IsAlreadyExists(err error) bool {
+	if all, isMulti := isAllMulti(err, IsAlreadyExists); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*alreadyExists)
+	return ok
+}
+
+// notSupported represents an error when something is not supported.
+type notSupported struct {
+	Err
+}
+
+// NotSupportedf returns an error which satisfies IsNotSupported().
+func NotSupportedf(format string, args ...interface{}) error {
+	err := &notSupported{Err: NewErr(format+" not supported", args...)}
+	err.typeTag = "notSupported"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotSupported returns an error which wraps err, has the given message
+// and satisfies IsNotSupported().
+func NewNotSupported(cause error, msg string) error {
+	err := &notSupported{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notSupported"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotSupported reports whether err was created with NotSupportedf() or
+// NewNotSupported().
+func IsNotSupported(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotSupported); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notSupported)
+	return ok
+}
+
+// notValid represents an error when something is not valid.
+type notValid struct {
+	Err
+}
+
+// NotValidf returns an error which satisfies IsNotValid().
+func NotValidf(format string, args ...interface{}) error {
+	err := &notValid{Err: NewErr(format+" not valid", args...)}
+	err.typeTag = "notValid"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotValid returns an error which wraps err, has the given message and
+// satisfies IsNotValid().
+func NewNotValid(cause error, msg string) error {
+	err := &notValid{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notValid"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotValid reports whether err was created with NotValidf() or
+// NewNotValid().
+func IsNotValid(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotValid); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notValid)
+	return ok
+}
+
+// notProvisioned represents an error when something is not yet provisioned.
+type notProvisioned struct {
+	Err
+}
+
+// NotProvisionedf returns an error which satisfies IsNotProvisioned().
+func NotProvisionedf(format string, args ...interface{}) error {
+	err := &notProvisioned{Err: NewErr(format+" not provisioned", args...)}
+	err.typeTag = "notProvisioned"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotProvisioned returns an error which wraps err, has the given message
+// and satisfies IsNotProvisioned().
+func NewNotProvisioned(cause error, msg string) error {
+	err := &notProvisioned{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notProvisioned"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotProvisioned reports whether err was created with NotProvisionedf()
+// or NewNotProvisioned().
+func IsNotProvisioned(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotProvisioned); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notProvisioned)
+	return ok
+}
+
+// notAssigned represents an error when something is not yet assigned.
+type notAssigned struct {
+	Err
+}
+
+// NotAssignedf returns an error which satisfies IsNotAssigned().
+func NotAssignedf(format string, args ...interface{}) error {
+	err := &notAssigned{Err: NewErr(format+" not assigned", args...)}
+	err.typeTag = "notAssigned"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotAssigned returns an error which wraps err, has the given message
+// and satisfies IsNotAssigned().
+func NewNotAssigned(cause error, msg string) error {
+	err := &notAssigned{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notAssigned"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotAssigned reports whether err was created with NotAssignedf() or
+// NewNotAssigned().
+func IsNotAssigned(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotAssigned); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notAssigned)
+	return ok
+}
+
+// methodNotAllowed represents an error when a particular method is not
+// allowed.
+type methodNotAllowed struct {
+	Err
+}
+
+// MethodNotAllowedf returns an error which satisfies IsMethodNotAllowed().
+func MethodNotAllowedf(format string, args ...interface{}) error {
+	err := &methodNotAllowed{Err: NewErr(format, args...)}
+	err.typeTag = "methodNotAllowed"
+	err.SetLocation(1)
+	return err
+}
+
+// NewMethodNotAllowed returns an error which wraps err, has the given
+// message and satisfies IsMethodNotAllowed().
+func NewMethodNotAllowed(cause error, msg string) error {
+	err := &methodNotAllowed{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "methodNotAllowed"
+	err.SetLocation(1)
+	return err
+}
+
+// IsMethodNotAllowed reports whether err was created with
+// MethodNotAllowedf() or NewMethodNotAllowed().
+func IsMethodNotAllowed(err error) bool {
+	if all, isMulti := isAllMulti(err, IsMethodNotAllowed); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*methodNotAllowed)
+	return ok
+}
+
+// badRequest represents an error when a request has invalid parameters.
+type badRequest struct {
+	Err
+}
+
+// BadRequestf returns an error which satisfies IsBadRequest().
+func BadRequestf(format string, args ...interface{}) error {
+	err := &badRequest{Err: NewErr(format, args...)}
+	err.typeTag = "badRequest"
+	err.SetLocation(1)
+	return err
+}
+
+// NewBadRequest returns an error which wraps err, has the given message and
+// satisfies IsBadRequest().
+func NewBadRequest(cause error, msg string) error {
+	err := &badRequest{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "badRequest"
+	err.SetLocation(1)
+	return err
+}
+
+// IsBadRequest reports whether err was created with BadRequestf() or
+// NewBadRequest().
+func IsBadRequest(err error) bool {
+	if all, isMulti := isAllMulti(err, IsBadRequest); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*badRequest)
+	return ok
+}
+
+// forbidden represents an error when a request is forbidden.
+type forbidden struct {
+	Err
+}
+
+// Forbiddenf returns an error which satisfies IsForbidden().
+func Forbiddenf(format string, args ...interface{}) error {
+	err := &forbidden{Err: NewErr(format, args...)}
+	err.typeTag = "forbidden"
+	err.SetLocation(1)
+	return err
+}
+
+// NewForbidden returns an error which wraps err, has the given message and
+// satisfies IsForbidden().
+func NewForbidden(cause error, msg string) error {
+	err := &forbidden{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "forbidden"
+	err.SetLocation(1)
+	return err
+}
+
+// IsForbidden reports whether err was created with Forbiddenf() or
+// NewForbidden().
+func IsForbidden(err error) bool {
+	if all, isMulti := isAllMulti(err, IsForbidden); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*forbidden)
+	return ok
+}
+
+// quotaLimitExceeded represents an error when an operation could not be
+// completed because a quota limit was exceeded.
+type quotaLimitExceeded struct {
+	Err
+}
+
+// QuotaLimitExceededf returns an error which satisfies
+// IsQuotaLimitExceeded().
+func QuotaLimitExceededf(format string, args ...interface{}) error {
+	err := &quotaLimitExceeded{Err: NewErr(format, args...)}
+	err.typeTag = "quotaLimitExceeded"
+	err.SetLocation(1)
+	return err
+}
+
+// NewQuotaLimitExceeded returns an error which wraps err, has the given
+// message and satisfies IsQuotaLimitExceeded().
+func NewQuotaLimitExceeded(cause error, msg string) error {
+	err := &quotaLimitExceeded{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "quotaLimitExceeded"
+	err.SetLocation(1)
+	return err
+}
+
+// IsQuotaLimitExceeded reports whether err was created with
+// QuotaLimitExceededf() or NewQuotaLimitExceeded().
+func IsQuotaLimitExceeded(err error) bool {
+	if all, isMulti := isAllMulti(err, IsQuotaLimitExceeded); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*quotaLimitExceeded)
+	return ok
+}
+
+// notYetAvailable represents an error when something is not yet available.
+type notYetAvailable struct {
+	Err
+}
+
+// NotYetAvailablef returns an error which satisfies IsNotYetAvailable().
+func NotYetAvailablef(format string, args ...interface{}) error {
+	err := &notYetAvailable{Err: NewErr(format, args...)}
+	err.typeTag = "notYetAvailable"
+	err.SetLocation(1)
+	return err
+}
+
+// NewNotYetAvailable returns an error which wraps err, has the given
+// message and satisfies IsNotYetAvailable().
+func NewNotYetAvailable(cause error, msg string) error {
+	err := &notYetAvailable{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "notYetAvailable"
+	err.SetLocation(1)
+	return err
+}
+
+// IsNotYetAvailable reports whether err was created with
+// NotYetAvailablef() or NewNotYetAvailable().
+func IsNotYetAvailable(err error) bool {
+	if all, isMulti := isAllMulti(err, IsNotYetAvailable); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*notYetAvailable)
+	return ok
+}
+
+// timeout represents an error when an operation times out.
+type timeout struct {
+	Err
+}
+
+// Timeoutf returns an error which satisfies IsTimeout().
+func Timeoutf(format string, args ...interface{}) error {
+	err := &timeout{Err: NewErr(format+" timeout", args...)}
+	err.typeTag = "timeout"
+	err.SetLocation(1)
+	return err
+}
+
+// NewTimeout returns an error which wraps err, has the given message and
+// satisfies IsTimeout().
+func NewTimeout(cause error, msg string) error {
+	err := &timeout{Err: Err{message: wrapMessage(cause, msg), previous: cause}}
+	err.typeTag = "timeout"
+	err.SetLocation(1)
+	return err
+}
+
+// IsTimeout reports whether err was created with Timeoutf() or
+// NewTimeout().
+func IsTimeout(err error) bool {
+	if all, isMulti := isAllMulti(err, IsTimeout); isMulti {
+		return all
+	}
+	err = Cause(err)
+	_, ok := err.(*timeout)
+	return ok
+}
@@ -0,0 +1,369 @@
+// Copyright 2013, 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+)
+
+// New is a drop in replacement for the standard library errors module that
+// records the location that the error is created.
+//
+// For example:
+//
+//	return errors.New("validation failed")
+func New(message string) error {
+	err := &Err{message: message}
+	err.SetLocation(1)
+	return err
+}
+
+// Errorf creates a new annotated error and records the location that the
+// error is created. This should be a drop in replacement for fmt.Errorf.
+//
+// For example:
+//
+//	return errors.Errorf("validation failed: %s", message)
+func Errorf(format string, args ...interface{}) error {
+	err := &Err{message: fmt.Sprintf(format, args...)}
+	err.SetLocation(1)
+	return err
+}
+
+// Trace adds the location of the Trace call to the stack. The Cause of the
+// resulting error is the same as the error parameter. If the error
+// parameter is nil, the result will be nil.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Trace(err)
+//	}
+func Trace(other error) error {
+	if other == nil {
+		return nil
+	}
+	err := &Err{
+		message:  other.Error(),
+		previous: other,
+		cause:    Cause(other),
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Annotate is used to add extra context to an existing error. The location
+// (file and line) of the Annotate call is recorded with the annotations.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Annotate(err, "failed to frombulate")
+//	}
+func Annotate(other error, message string) error {
+	if other == nil {
+		return nil
+	}
+	err := &Err{
+		message:  message + ": " + other.Error(),
+		previous: other,
+		cause:    Cause(other),
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Annotatef is used to add extra context to an existing error. The location
+// (file and line) of the Annotatef call is recorded with the annotations.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Annotatef(err, "failed to frombulate the %s", arg)
+//	}
+func Annotatef(other error, format string, args ...interface{}) error {
+	if other == nil {
+		return nil
+	}
+	err := &Err{
+		message:  fmt.Sprintf(format, args...) + ": " + other.Error(),
+		previous: other,
+		cause:    Cause(other),
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// DeferredAnnotatef annotates the given error (when it is not nil) with the
+// given format string and arguments (like fmt.Sprintf). If *err is nil,
+// DeferredAnnotatef does nothing. This method is used in a defer statement
+// in order to annotate any resulting error with the same message.
+//
+// For example:
+//
+//	defer DeferredAnnotatef(&err, "failed to frombulate the %s", arg)
+func DeferredAnnotatef(err *error, format string, args ...interface{}) {
+	if *err == nil {
+		return
+	}
+	newErr := &Err{
+		message:  fmt.Sprintf(format, args...) + ": " + (*err).Error(),
+		previous: *err,
+		cause:    Cause(*err),
+	}
+	newErr.SetLocation(1)
+	*err = newErr
+}
+
+// Wrap changes the Cause of the error. The location of the Wrap call is
+// also stored in the error stack.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    newErr := &MyError{"more context", err}
+//	    return errors.Wrap(err, newErr)
+//	}
+func Wrap(other, newDescriptive error) error {
+	err := &Err{
+		message:  newDescriptive.Error(),
+		previous: other,
+		cause:    newDescriptive,
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Wrapf changes the Cause of the error, and adds an annotation. The
+// location of the Wrap call is also stored in the error stack.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Wrapf(err, newDescriptive, "invalid value %q", value)
+//	}
+func Wrapf(other, newDescriptive error, format string, args ...interface{}) error {
+	err := &Err{
+		message:  fmt.Sprintf(format, args...) + ": " + newDescriptive.Error(),
+		previous: other,
+		cause:    newDescriptive,
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Mask hides the given error by replacing it with a new error that has the
+// same error message, but no cause. This is useful when you want to
+// prevent errors.Cause from finding the original error, while still
+// recording the location of the error and making it part of the error
+// stack.
+//
+// Because the masking *Err still records other as its previous error,
+// errors.Is and errors.As can traverse all the way through to other and
+// anything it wraps; only errors.Cause is blocked. If you want to hide
+// other from errors.Is and errors.As too, except for a chosen set of
+// sentinels, use MaskPreserving instead.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Mask(err)
+//	}
+func Mask(other error) error {
+	if other == nil {
+		return nil
+	}
+	err := &Err{
+		message:  other.Error(),
+		previous: other,
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Maskf masks the given error with the given format string and arguments;
+// the resulting error does not return the underlying error when Cause is
+// called on it.
+//
+// For example:
+//
+//	if err := SomeFunc(); err != nil {
+//	    return errors.Maskf(err, "failed to frombulate")
+//	}
+func Maskf(other error, format string, args ...interface{}) error {
+	if other == nil {
+		return nil
+	}
+	err := &Err{
+		message:  fmt.Sprintf(format, args...) + ": " + other.Error(),
+		previous: other,
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// Cause returns the cause of the given error. If the given error implements
+// the interface:
+//
+//	type causer interface {
+//	       Cause() error
+//	}
+//
+// and its Cause method returns non-nil, then Cause will recurse no
+// further and return that error instead. Otherwise Cause returns the
+// error itself.
+//
+// Cause is the usual way to diagnose errors that may have been wrapped by
+// Annotate, Trace or Mask.
+func Cause(err error) error {
+	var diag error
+	if err, ok := err.(causer); ok {
+		diag = err.Cause()
+	}
+	if diag != nil {
+		return diag
+	}
+	return err
+}
+
+// SetLocation records the location of the call callDepth levels above the
+// call to SetLocation, for any error that can record it. If err is nil,
+// or does not support recording a location, SetLocation is a no-op.
+//
+// For example:
+//
+//	err := somePackage.DoSomething() // a non-*Err error
+//	return errors.SetLocation(err, 0)
+func SetLocation(err error, callDepth int) error {
+	if err == nil {
+		return nil
+	}
+	if setter, ok := err.(locationSetter); ok {
+		setter.SetLocation(callDepth)
+	}
+	return err
+}
+
+// Details returns information about the stack of errors wrapped by err, in
+// the format:
+//
+//	[{filename:99: error one} {filename:42: cause of error one}]
+//
+// This is a terse alternative to ErrorStack as it returns a single line.
+// If err is, or wraps, a *Multi, Details recurses into every error it
+// collected.
+func Details(err error) string {
+	if err == nil {
+		return "[]"
+	}
+	var parts []string
+	for {
+		if m, ok := err.(*Multi); ok {
+			childParts := make([]string, len(m.errs))
+			for i, child := range m.errs {
+				childParts[i] = Details(child)
+			}
+			parts = append(parts, strings.Join(childParts, " "))
+			break
+		}
+
+		var buf strings.Builder
+		if locer, ok := err.(Locationer); ok {
+			if file, line := locer.Location(); file != "" {
+				fmt.Fprintf(&buf, "%s:%d: ", file, line)
+			}
+		}
+		buf.WriteString(err.Error())
+		parts = append(parts, buf.String())
+
+		w, ok := err.(wrapper)
+		if !ok {
+			break
+		}
+		next := w.Underlying()
+		if next == nil {
+			break
+		}
+		err = next
+	}
+	return "[{" + strings.Join(parts, "} {") + "}]"
+}
+
+// ErrorStack returns a string representation of the annotated error, with
+// one line per level of annotation, ordered from the original error to the
+// most recent annotation. If the error passed in is not an *Err, the
+// result is simply the result of the Error() method. If err is, or wraps,
+// a *Multi, the lines for each collected error are printed indented and
+// tagged with its index, under a header giving the aggregation's own
+// location and error count.
+func ErrorStack(err error) string {
+	return strings.Join(errorStack(err), "\n")
+}
+
+func errorStack(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	// Build up the lines innermost-first, then reverse them so the
+	// original error comes first.
+	var lines []string
+	for {
+		if m, ok := err.(*Multi); ok {
+			// multiErrorStack is already in final, top-to-bottom order;
+			// reverse it here so the reversal below restores that order.
+			block := multiErrorStack(m)
+			for i, j := 0, len(block)-1; i < j; i, j = i+1, j-1 {
+				block[i], block[j] = block[j], block[i]
+			}
+			lines = append(lines, block...)
+			break
+		}
+
+		var buf strings.Builder
+		if cerr, ok := err.(*Err); ok {
+			if file, line := cerr.Location(); file != "" {
+				fmt.Fprintf(&buf, "%s:%d", file, line)
+			}
+
+			message := cerr.localMessage()
+			if buf.Len() > 0 {
+				buf.WriteString(": ")
+			}
+			buf.WriteString(message)
+		} else {
+			buf.WriteString(err.Error())
+		}
+		lines = append(lines, buf.String())
+
+		w, ok := err.(wrapper)
+		if !ok {
+			break
+		}
+		next := w.Underlying()
+		if next == nil {
+			break
+		}
+		err = next
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// Is is a drop in replacement for the standard library errors.Is, provided
+// so callers need only import this package.
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As is a drop in replacement for the standard library errors.As, provided
+// so callers need only import this package.
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
@@ -0,0 +1,284 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build juju_errors_nolocation
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+// assertNoLocation checks that err records no file:line, the way every
+// *Err must under the juju_errors_nolocation build tag.
+func assertNoLocation(c *gc.C, err error) {
+	locer, ok := err.(errors.Locationer)
+	c.Assert(ok, gc.Equals, true)
+	file, line := locer.Location()
+	c.Assert(file, gc.Equals, "")
+	c.Assert(line, gc.Equals, 0)
+}
+
+func (*functionSuite) TestNew(c *gc.C) {
+	err := errors.New("testing")
+
+	c.Assert(err.Error(), gc.Equals, "testing")
+	c.Assert(errors.Cause(err), gc.Equals, err)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestErrorf(c *gc.C) {
+	err := errors.Errorf("testing %d", 42)
+
+	c.Assert(err.Error(), gc.Equals, "testing 42")
+	c.Assert(errors.Cause(err), gc.Equals, err)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestTrace(c *gc.C) {
+	first := errors.New("first")
+	err := errors.Trace(first)
+
+	c.Assert(err.Error(), gc.Equals, "first")
+	c.Assert(errors.Cause(err), gc.Equals, first)
+	assertNoLocation(c, err)
+
+	c.Assert(errors.Trace(nil), gc.IsNil)
+}
+
+func (*functionSuite) TestAnnotate(c *gc.C) {
+	first := errors.New("first")
+	err := errors.Annotate(first, "annotation")
+
+	c.Assert(err.Error(), gc.Equals, "annotation: first")
+	c.Assert(errors.Cause(err), gc.Equals, first)
+	assertNoLocation(c, err)
+
+	c.Assert(errors.Annotate(nil, "annotate"), gc.IsNil)
+}
+
+func (*functionSuite) TestAnnotatef(c *gc.C) {
+	first := errors.New("first")
+	err := errors.Annotatef(first, "annotation %d", 2) //err annotatefTest
+
+	c.Assert(err.Error(), gc.Equals, "annotation 2: first")
+	c.Assert(errors.Cause(err), gc.Equals, first)
+	assertNoLocation(c, err)
+
+	c.Assert(errors.Annotatef(nil, "annotate"), gc.IsNil)
+}
+
+func (*functionSuite) TestWrap(c *gc.C) {
+	first := errors.New("first")
+	detailed := errors.New("detailed")
+	err := errors.Wrap(first, detailed)
+
+	c.Assert(err.Error(), gc.Equals, "detailed")
+	c.Assert(errors.Cause(err), gc.Equals, detailed)
+	assertNoLocation(c, first)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestWrapOfNil(c *gc.C) {
+	detailed := errors.New("detailed")
+	err := errors.Wrap(nil, detailed)
+	c.Assert(err.Error(), gc.Equals, "detailed")
+	c.Assert(errors.Cause(err), gc.Equals, detailed)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestWrapf(c *gc.C) {
+	first := errors.New("first")
+	detailed := errors.New("detailed")
+	err := errors.Wrapf(first, detailed, "value %d", 42)
+	c.Assert(err.Error(), gc.Equals, "value 42: detailed")
+	c.Assert(errors.Cause(err), gc.Equals, detailed)
+	assertNoLocation(c, first)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestWrapfOfNil(c *gc.C) {
+	detailed := errors.New("detailed")
+	err := errors.Wrapf(nil, detailed, "value %d", 42)
+	c.Assert(err.Error(), gc.Equals, "value 42: detailed")
+	c.Assert(errors.Cause(err), gc.Equals, detailed)
+	assertNoLocation(c, err)
+}
+
+func (*functionSuite) TestMask(c *gc.C) {
+	first := errors.New("first")
+	err := errors.Mask(first)
+	c.Assert(err.Error(), gc.Equals, "first")
+	c.Assert(errors.Cause(err), gc.Equals, err)
+	assertNoLocation(c, err)
+
+	c.Assert(errors.Mask(nil), gc.IsNil)
+}
+
+func (*functionSuite) TestMaskf(c *gc.C) {
+	first := errors.New("first")
+	err := errors.Maskf(first, "masked %d", 42)
+	c.Assert(err.Error(), gc.Equals, "masked 42: first")
+	c.Assert(errors.Cause(err), gc.Equals, err)
+	assertNoLocation(c, err)
+
+	c.Assert(errors.Maskf(nil, "mask"), gc.IsNil)
+}
+
+// TestErrorStack mirrors the default build's table test of the same name,
+// but with every expected fragment built without a file:line, the way
+// ErrorStack prints when no error in the chain has a location.
+func (*functionSuite) TestErrorStack(c *gc.C) {
+	for i, test := range []struct {
+		message   string
+		generator func(*gc.C, io.Writer) error
+		tracer    bool
+	}{{
+		message: "nil",
+		generator: func(_ *gc.C, _ io.Writer) error {
+			return nil
+		},
+	}, {
+		message: "raw error",
+		generator: func(c *gc.C, expected io.Writer) error {
+			fmt.Fprint(expected, "raw")
+			return fmt.Errorf("raw")
+		},
+	}, {
+		message: "single error stack",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := errors.New("first error")
+			fmt.Fprint(expected, "first error")
+			return err
+		},
+		tracer: true,
+	}, {
+		message: "annotated error",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := errors.New("first error")
+			fmt.Fprint(expected, "first error\n")
+			err = errors.Annotate(err, "annotation")
+			fmt.Fprint(expected, "annotation")
+			return err
+		},
+		tracer: true,
+	}, {
+		message: "wrapped error",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := errors.New("first error")
+			fmt.Fprint(expected, "first error\n")
+			err = errors.Wrap(err, newError("detailed error"))
+			fmt.Fprint(expected, "detailed error")
+			return err
+		},
+		tracer: true,
+	}, {
+		message: "annotated wrapped error",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := errors.Errorf("first error")
+			fmt.Fprint(expected, "first error\n")
+			err = errors.Wrap(err, fmt.Errorf("detailed error"))
+			fmt.Fprint(expected, "detailed error\n")
+			err = errors.Annotatef(err, "annotated")
+			fmt.Fprint(expected, "annotated")
+			return err
+		},
+		tracer: true,
+	}, {
+		message: "traced, and annotated",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := errors.New("first error")
+			fmt.Fprint(expected, "first error\n")
+			err = errors.Trace(err)
+			fmt.Fprint(expected, "\n")
+			err = errors.Annotate(err, "some context")
+			fmt.Fprint(expected, "some context\n")
+			err = errors.Trace(err)
+			fmt.Fprint(expected, "\n")
+			err = errors.Annotate(err, "more context")
+			fmt.Fprint(expected, "more context\n")
+			err = errors.Trace(err)
+			return err
+		},
+		tracer: true,
+	}, {
+		message: "uncomparable, wrapped with a value error",
+		generator: func(c *gc.C, expected io.Writer) error {
+			err := newNonComparableError("first error")
+			fmt.Fprintln(expected, "first error")
+			err = errors.Trace(err)
+			fmt.Fprint(expected, "\n")
+			err = errors.Wrap(err, newError("value error"))
+			fmt.Fprint(expected, "value error\n")
+			err = errors.Maskf(err, "masked")
+			fmt.Fprint(expected, "masked\n")
+			err = errors.Annotate(err, "more context")
+			fmt.Fprint(expected, "more context\n")
+			err = errors.Trace(err)
+			return err
+		},
+		tracer: true,
+	}} {
+		c.Logf("%v: %s", i, test.message)
+		expected := strings.Builder{}
+		err := test.generator(c, &expected)
+		stack := errors.ErrorStack(err)
+		ok := c.Check(stack, gc.Equals, expected.String())
+		if !ok {
+			c.Logf("%#v", err)
+		}
+		tracer, ok := err.(tracer)
+		c.Check(ok, gc.Equals, test.tracer)
+		if ok {
+			stackTrace := tracer.StackTrace()
+			c.Check(stackTrace, gc.DeepEquals, strings.Split(stack, "\n"))
+		}
+	}
+}
+
+func (*functionSuite) TestFormat(c *gc.C) {
+	formatErrorExpected := &strings.Builder{}
+	err := errors.New("TestFormat")
+	fmt.Fprint(formatErrorExpected, "TestFormat\n")
+	err = errors.Mask(err)
+
+	for i, test := range []struct {
+		format string
+		expect string
+	}{{
+		format: "%s",
+		expect: "TestFormat",
+	}, {
+		format: "%v",
+		expect: "TestFormat",
+	}, {
+		format: "%q",
+		expect: `"TestFormat"`,
+	}, {
+		format: "%A",
+		expect: `%!A(*errors.Err=TestFormat)`,
+	}, {
+		format: "%+v",
+		expect: formatErrorExpected.String(),
+	}} {
+		c.Logf("test %d: %q", i, test.format)
+		s := fmt.Sprintf(test.format, err)
+		c.Check(s, gc.Equals, test.expect)
+	}
+}
+
+func (*functionSuite) TestSetLocation(c *gc.C) {
+	err := errors.New("test")
+	err = errors.SetLocation(err, 1)
+	_, implements := err.(errors.Locationer)
+	c.Assert(implements, gc.Equals, true)
+
+	c.Check(errors.ErrorStack(err), gc.Equals, "test")
+	assertNoLocation(c, err)
+}
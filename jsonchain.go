@@ -0,0 +1,211 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import "encoding/json"
+
+// jsonCode is the wire representation of a Code attached with WithCode.
+type jsonCode struct {
+	Scope    uint32   `json:"scope"`
+	Category Category `json:"category"`
+	Detail   uint32   `json:"detail"`
+}
+
+// jsonFrame is the wire representation of one level of an annotation/
+// trace chain, in the same order as ErrorStack: the original error
+// first, each subsequent annotation after it.
+type jsonFrame struct {
+	// Message is this frame's own contribution to the error text, with
+	// the previous frame's text trimmed off the end - the same text
+	// ErrorStack prints for this frame. It is "" for a frame that added
+	// no text of its own, such as one created by Trace or Mask.
+	Message string `json:"message"`
+
+	// File and Line are this frame's location, if it recorded one.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// Type is the tag of the typed error (see typeConstructors) this
+	// frame was built with, such as "notFound" or "unauthorized", or ""
+	// if this frame is a plain *Err.
+	Type string `json:"type,omitempty"`
+
+	// Masked records whether this frame's Cause was deliberately cut
+	// off from its previous error, the way Mask and Maskf do.
+	Masked bool `json:"masked,omitempty"`
+
+	// Code is the structured code attached to this frame with WithCode,
+	// if any.
+	Code *jsonCode `json:"code,omitempty"`
+}
+
+// jsonChain is the top-level schema produced by MarshalJSON.
+type jsonChain struct {
+	Frames []jsonFrame `json:"frames"`
+}
+
+// typeConstructors maps each typed-error tag recorded in Err.typeTag (see
+// errortypes.go) to a function rebuilding that type around a
+// reconstructed Err. When adding a new typed error to errortypes.go, add
+// it here too so it survives the round trip.
+var typeConstructors = map[string]func(Err) error{
+	"timeout":            func(e Err) error { return &timeout{Err: e} },
+	"notFound":           func(e Err) error { return &notFound{Err: e} },
+	"userNotFound":       func(e Err) error { return &userNotFound{Err: e} },
+	"unauthorized":       func(e Err) error { return &unauthorized{Err: e} },
+	"notImplemented":     func(e Err) error { return &notImplemented{Err: e} },
+	"alreadyExists":      func(e Err) error { return &alreadyExists{Err: e} },
+	"notSupported":       func(e Err) error { return &notSupported{Err: e} },
+	"notValid":           func(e Err) error { return &notValid{Err: e} },
+	"notProvisioned":     func(e Err) error { return &notProvisioned{Err: e} },
+	"notAssigned":        func(e Err) error { return &notAssigned{Err: e} },
+	"methodNotAllowed":   func(e Err) error { return &methodNotAllowed{Err: e} },
+	"badRequest":         func(e Err) error { return &badRequest{Err: e} },
+	"forbidden":          func(e Err) error { return &forbidden{Err: e} },
+	"quotaLimitExceeded": func(e Err) error { return &quotaLimitExceeded{Err: e} },
+	"notYetAvailable":    func(e Err) error { return &notYetAvailable{Err: e} },
+}
+
+// typeTagFor returns the typed-error tag recorded on err, or "" if err is
+// not one of this package's typed errors.
+func typeTagFor(err error) string {
+	if e, ok := err.(interface{ rawTypeTag() string }); ok {
+		return e.rawTypeTag()
+	}
+	return ""
+}
+
+// typeTagOf walks err's annotation stack from the outside in, the same
+// direction CodeOf does, and returns the first typed-error tag it finds,
+// or "" if no frame in the stack is one of this package's typed errors.
+func typeTagOf(err error) string {
+	for err != nil {
+		if tag := typeTagFor(err); tag != "" {
+			return tag
+		}
+		w, ok := err.(wrapper)
+		if !ok {
+			break
+		}
+		err = w.Underlying()
+	}
+	return ""
+}
+
+// constructTyped rebuilds the typed error tagged tag around e, or
+// returns nil if tag is not recognised.
+func constructTyped(tag string, e Err) error {
+	if construct, ok := typeConstructors[tag]; ok {
+		return construct(e)
+	}
+	return nil
+}
+
+// MarshalJSON serializes the annotation/trace chain rooted at err into a
+// stable JSON schema: one frame per level of annotation, from the
+// original error to the most recent one, with each frame's own message,
+// location, typed-error tag (if any) and attached Code (if any). If err
+// is nil, MarshalJSON returns the JSON null literal.
+//
+// Use UnmarshalJSON to reconstruct the chain on the other side of a log
+// line or an RPC boundary; the round trip preserves Error(), ErrorStack,
+// errors.Is, errors.As, the IsNotFound family of satisfiers, and any
+// code attached with WithCode. It does not preserve a cause substituted
+// with Wrap or Wrapf - those reconstruct with the default Cause a plain
+// annotation would have, and with an Error() text that has the previous
+// error's text appended after ": " - and it does not preserve the sentinels given
+// to MaskPreserving or MaskfPreserving, whose message survives but whose
+// hidden error does not.
+func MarshalJSON(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	type localMessager interface{ localMessage() string }
+	type causeSeverer interface{ causeSevered() bool }
+
+	var frames []jsonFrame
+	for {
+		frame := jsonFrame{Message: err.Error()}
+		if lm, ok := err.(localMessager); ok {
+			frame.Message = lm.localMessage()
+		}
+		if locer, ok := err.(Locationer); ok {
+			frame.File, frame.Line = locer.Location()
+		}
+		if cs, ok := err.(causeSeverer); ok {
+			frame.Masked = cs.causeSevered()
+		}
+		frame.Type = typeTagFor(err)
+		if coder, ok := err.(Coder); ok {
+			if code, has := coder.Code(); has {
+				frame.Code = &jsonCode{Scope: code.Scope, Category: code.Category, Detail: code.Detail}
+			}
+		}
+
+		frames = append(frames, frame)
+
+		w, ok := err.(wrapper)
+		if !ok {
+			break
+		}
+		next := w.Underlying()
+		if next == nil {
+			break
+		}
+		err = next
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+
+	return json.Marshal(jsonChain{Frames: frames})
+}
+
+// UnmarshalJSON reconstructs the chain of errors serialized by
+// MarshalJSON, rebuilding each frame from the original error outward and
+// re-deriving its Cause exactly as Annotate, Trace, Mask and the typed-
+// error constructors do - see MarshalJSON for the limits of this round
+// trip. It returns a nil error, and no error, for the JSON null literal.
+func UnmarshalJSON(data []byte) (error, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	var chain jsonChain
+	if err := json.Unmarshal(data, &chain); err != nil {
+		return nil, err
+	}
+
+	var built error
+	for _, frame := range chain.Frames {
+		var message string
+		switch {
+		case built == nil:
+			message = frame.Message
+		case frame.Message == "":
+			message = built.Error()
+		default:
+			message = frame.Message + ": " + built.Error()
+		}
+
+		e := Err{message: message, previous: built, file: frame.File, line: frame.Line, typeTag: frame.Type}
+		if !frame.Masked && frame.Type == "" {
+			e.cause = Cause(built)
+		}
+		if frame.Code != nil {
+			code := Code{Scope: frame.Code.Scope, Category: frame.Code.Category, Detail: frame.Code.Detail}
+			e.code = &code
+		}
+
+		if constructed := constructTyped(frame.Type, e); constructed != nil {
+			built = constructed
+		} else {
+			built = &e
+		}
+	}
+
+	return built, nil
+}
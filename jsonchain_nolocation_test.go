@@ -0,0 +1,38 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build juju_errors_nolocation
+
+package errors_test
+
+import (
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+// TestUnmarshalJSONLocationerAndTracer confirms that a reconstructed
+// chain still satisfies this package's location and stack-trace
+// interfaces under the juju_errors_nolocation build tag, where neither
+// the original nor the reconstructed chain ever carries a location.
+func (*jsonChainSuite) TestUnmarshalJSONLocationerAndTracer(c *gc.C) {
+	err := errors.Annotate(errors.New("first error"), "annotation")
+
+	data, marshalErr := errors.MarshalJSON(err)
+	c.Assert(marshalErr, gc.IsNil)
+
+	got, unmarshalErr := errors.UnmarshalJSON(data)
+	c.Assert(unmarshalErr, gc.IsNil)
+
+	locer, ok := got.(errors.Locationer)
+	c.Assert(ok, gc.Equals, true)
+	file, line := locer.Location()
+	c.Assert(file, gc.Equals, "")
+	c.Assert(line, gc.Equals, 0)
+
+	tr, ok := got.(tracer)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(strings.Join(tr.StackTrace(), "\n"), gc.Equals, errors.ErrorStack(got))
+}
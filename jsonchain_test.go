@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type jsonChainSuite struct{}
+
+var _ = gc.Suite(&jsonChainSuite{})
+
+func (*jsonChainSuite) TestMarshalJSONNil(c *gc.C) {
+	data, err := errors.MarshalJSON(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "null")
+}
+
+func (*jsonChainSuite) TestUnmarshalJSONNull(c *gc.C) {
+	got, err := errors.UnmarshalJSON([]byte("null"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.IsNil)
+}
+
+func (*jsonChainSuite) TestMarshalJSONGolden(c *gc.C) {
+	errors.SetStackMode(errors.ModeOff)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	err := errors.NotFoundf("widget")
+	err = errors.WithCode(err, 7, errors.CategoryResource, 3)
+	err = errors.Annotate(err, "looking up order")
+
+	data, marshalErr := errors.MarshalJSON(err)
+	c.Assert(marshalErr, gc.IsNil)
+
+	var got, want bytes.Buffer
+	c.Assert(json.Indent(&got, data, "", "  "), gc.IsNil)
+
+	golden, readErr := os.ReadFile("testdata/chain.golden.json")
+	c.Assert(readErr, gc.IsNil)
+	c.Assert(json.Indent(&want, golden, "", "  "), gc.IsNil)
+
+	c.Assert(got.String(), gc.Equals, strings.TrimRight(want.String(), "\n"))
+}
+
+func (*jsonChainSuite) TestRoundTripPreservesErrorAndStack(c *gc.C) {
+	errors.SetStackMode(errors.ModeOff)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	err := errors.New("first error")
+	err = errors.Trace(err)
+	err = errors.Annotate(err, "some context")
+	err = errors.Maskf(err, "masked")
+	err = errors.Annotate(err, "more context")
+
+	data, marshalErr := errors.MarshalJSON(err)
+	c.Assert(marshalErr, gc.IsNil)
+
+	got, unmarshalErr := errors.UnmarshalJSON(data)
+	c.Assert(unmarshalErr, gc.IsNil)
+
+	c.Assert(got.Error(), gc.Equals, err.Error())
+	c.Assert(errors.ErrorStack(got), gc.Equals, errors.ErrorStack(err))
+}
+
+func (*jsonChainSuite) TestRoundTripPreservesMaskedCause(c *gc.C) {
+	err := errors.Maskf(errors.New("secret"), "public message")
+
+	data, marshalErr := errors.MarshalJSON(err)
+	c.Assert(marshalErr, gc.IsNil)
+
+	got, unmarshalErr := errors.UnmarshalJSON(data)
+	c.Assert(unmarshalErr, gc.IsNil)
+
+	c.Assert(errors.Cause(got), gc.Equals, got)
+}
+
+func (*jsonChainSuite) TestRoundTripPreservesTypedErrorSatisfiers(c *gc.C) {
+	for _, errInfo := range allErrors {
+		err := errInfo.argsConstructor("thing", 1)
+		err = errors.Annotate(err, "context")
+
+		data, marshalErr := errors.MarshalJSON(err)
+		c.Assert(marshalErr, gc.IsNil)
+
+		got, unmarshalErr := errors.UnmarshalJSON(data)
+		c.Assert(unmarshalErr, gc.IsNil)
+
+		mustSatisfy(c, got, errInfo)
+		c.Check(got.Error(), gc.Equals, err.Error())
+	}
+}
+
+func (*jsonChainSuite) TestRoundTripPreservesCode(c *gc.C) {
+	err := errors.WithCode(errors.New("boom"), 1, errors.CategoryAuth, 2)
+
+	data, marshalErr := errors.MarshalJSON(err)
+	c.Assert(marshalErr, gc.IsNil)
+
+	got, unmarshalErr := errors.UnmarshalJSON(data)
+	c.Assert(unmarshalErr, gc.IsNil)
+
+	scope, category, detail, ok := errors.CodeOf(got)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(scope, gc.Equals, uint32(1))
+	c.Assert(category, gc.Equals, uint32(errors.CategoryAuth))
+	c.Assert(detail, gc.Equals, uint32(2))
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !juju_errors_nolocation
+
+package errors
+
+// locationRecordingEnabled is true in normal builds. It is a const so that
+// the body of (*Err).SetLocation compiles away entirely when built with
+// the juju_errors_nolocation tag - see location_nolocation.go.
+const locationRecordingEnabled = true
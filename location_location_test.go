@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !juju_errors_nolocation
+
+package errors_test
+
+import (
+	"sync"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+func (*stackModeSuite) TestErrorStackUnderModeEager(c *gc.C) {
+	errors.SetStackMode(errors.ModeEager)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	first := errors.New("first error")
+	loc1 := errorLocationValue(c)
+	err := errors.Annotate(first, "annotation")
+	loc2 := errorLocationValue(c)
+
+	c.Assert(errors.ErrorStack(err), gc.Equals, loc1+": first error\n"+loc2+": annotation")
+}
+
+// TestLocationConcurrentResolveIsRaceFree confirms that resolving a ModeLazy
+// error's location from many goroutines at once, the way a shared error
+// that is both logged and returned or retried would, does not race - run
+// with -race to check.
+func TestLocationConcurrentResolveIsRaceFree(t *testing.T) {
+	errors.SetStackMode(errors.ModeLazy)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	err := errors.New("shared error")
+	locer, ok := err.(errors.Locationer)
+	if !ok {
+		t.Fatal("*Err does not implement Locationer")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locer.Location()
+		}()
+	}
+	wg.Wait()
+
+	file, line := locer.Location()
+	if file == "" || line == 0 {
+		t.Fatalf("expected a resolved location, got %q:%d", file, line)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build juju_errors_nolocation
+
+package errors
+
+// Build with -tags juju_errors_nolocation to strip location recording
+// entirely: SetLocation becomes a no-op and Location/Details/ErrorStack
+// print without file:line. Use this when profiles show runtime.Caller
+// formatting as hot and the location information isn't needed.
+
+// locationRecordingEnabled is false under the juju_errors_nolocation build
+// tag, making (*Err).SetLocation's body unreachable and letting the
+// compiler elide it.
+const locationRecordingEnabled = false
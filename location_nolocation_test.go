@@ -0,0 +1,58 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build juju_errors_nolocation
+
+package errors_test
+
+import (
+	"sync"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+// TestErrorStackUnderModeEager confirms that, under the
+// juju_errors_nolocation build tag, ModeEager has no effect: location
+// recording stays off regardless of stack mode.
+func (*stackModeSuite) TestErrorStackUnderModeEager(c *gc.C) {
+	errors.SetStackMode(errors.ModeEager)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	first := errors.New("first error")
+	err := errors.Annotate(first, "annotation")
+
+	c.Assert(errors.ErrorStack(err), gc.Equals, "first error\nannotation")
+}
+
+// TestLocationConcurrentResolveIsRaceFree confirms that concurrently calling
+// Location() on an error built with the juju_errors_nolocation tag is
+// safe and always reports no location, since SetLocation never records a
+// program counter to resolve.
+func TestLocationConcurrentResolveIsRaceFree(t *testing.T) {
+	errors.SetStackMode(errors.ModeLazy)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	err := errors.New("shared error")
+	locer, ok := err.(errors.Locationer)
+	if !ok {
+		t.Fatal("*Err does not implement Locationer")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locer.Location()
+		}()
+	}
+	wg.Wait()
+
+	file, line := locer.Location()
+	if file != "" || line != 0 {
+		t.Fatalf("expected no location under the nolocation build tag, got %q:%d", file, line)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type stackModeSuite struct{}
+
+var _ = gc.Suite(&stackModeSuite{})
+
+func (*stackModeSuite) TestModeOffDropsLocation(c *gc.C) {
+	errors.SetStackMode(errors.ModeOff)
+	defer errors.SetStackMode(errors.ModeLazy)
+
+	err := errors.New("boom")
+	c.Assert(errors.ErrorStack(err), gc.Equals, "boom")
+	c.Assert(errors.Details(err), gc.Equals, "[{boom}]")
+}
+
+func BenchmarkNew(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("benchmark error")
+	}
+}
+
+func BenchmarkTrace(b *testing.B) {
+	err := errors.New("benchmark error")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Trace(err)
+	}
+}
+
+func BenchmarkAnnotate(b *testing.B) {
+	err := errors.New("benchmark error")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Annotate(err, "annotation")
+	}
+}
+
+func BenchmarkNewModeEager(b *testing.B) {
+	errors.SetStackMode(errors.ModeEager)
+	defer errors.SetStackMode(errors.ModeLazy)
+	for i := 0; i < b.N; i++ {
+		_ = errors.New("benchmark error")
+	}
+}
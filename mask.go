@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+)
+
+// maskedErr is returned by MaskPreserving and MaskfPreserving.
+type maskedErr struct {
+	Err
+
+	hidden    error
+	sentinels []error
+}
+
+// Is is consulted by the standard library's errors.Is. Unlike a plain
+// *Err, which exposes its entire previous chain to errors.Is, maskedErr
+// reports true only for a target that is one of the sentinels given to
+// MaskPreserving, and only if errors.Is actually finds that target in
+// the hidden error. If no sentinels were given, any target that errors.Is
+// finds in the hidden error matches.
+func (m *maskedErr) Is(target error) bool {
+	if len(m.sentinels) == 0 {
+		return stderrors.Is(m.hidden, target)
+	}
+	for _, sentinel := range m.sentinels {
+		if sentinel == target {
+			return stderrors.Is(m.hidden, target)
+		}
+	}
+	return false
+}
+
+// As is consulted by the standard library's errors.As. It reports true
+// only for a target whose pointed-to type is assignable from one of the
+// sentinels given to MaskPreserving, and only if errors.As actually finds
+// a match in the hidden error. If no sentinels were given, any match that
+// errors.As finds in the hidden error counts.
+func (m *maskedErr) As(target interface{}) bool {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return false
+	}
+	if len(m.sentinels) == 0 {
+		return stderrors.As(m.hidden, target)
+	}
+	elemType := rv.Elem().Type()
+	for _, sentinel := range m.sentinels {
+		if reflect.TypeOf(sentinel).AssignableTo(elemType) {
+			return stderrors.As(m.hidden, target)
+		}
+	}
+	return false
+}
+
+// MaskPreserving hides the given error much as Mask does: the returned
+// error has the same message as other, but its Cause is itself, not
+// other. Unlike Mask, it does not record other as its previous error, so
+// other does not appear in the stack reported by ErrorStack or Details.
+//
+// Unlike Mask, whose Unwrap exposes the entire hidden chain to the
+// standard library's errors.Is and errors.As, MaskPreserving only lets
+// errors.Is and errors.As see through to the given sentinels (and
+// whatever they in turn wrap). Everything else about other - its
+// message, its type, any other error in its chain - stays hidden. This
+// is for callers that want to hide the detail of an internal error while
+// still letting well-known conditions be recognised afterwards, for
+// example an idempotent-delete handler that wants to keep recognising
+// fs.ErrNotExist after wrapping it:
+//
+//	if err := os.Remove(path); err != nil {
+//	    return errors.MaskPreserving(err, fs.ErrNotExist)
+//	}
+//	...
+//	if errors.Is(err, fs.ErrNotExist) {
+//	    // still true, even though errors.Cause(err) is not fs.ErrNotExist
+//	}
+//
+// If no sentinels are given, errors.Is and errors.As traverse the whole
+// hidden chain, as if other had been passed to every call directly.
+func MaskPreserving(other error, sentinels ...error) error {
+	if other == nil {
+		return nil
+	}
+	err := &maskedErr{
+		Err:       Err{message: other.Error()},
+		hidden:    other,
+		sentinels: sentinels,
+	}
+	err.SetLocation(1)
+	return err
+}
+
+// MaskfPreserving masks the given error with the given format string and
+// arguments, the same way MaskPreserving does. It always lets errors.Is
+// and errors.As traverse the whole hidden chain; use MaskPreserving if
+// only specific sentinels should be recognised.
+//
+// For example:
+//
+//	if err := os.Remove(path); err != nil {
+//	    return errors.MaskfPreserving(err, "removing %s", path)
+//	}
+func MaskfPreserving(other error, format string, args ...interface{}) error {
+	if other == nil {
+		return nil
+	}
+	err := &maskedErr{
+		Err:    Err{message: fmt.Sprintf(format, args...) + ": " + other.Error()},
+		hidden: other,
+	}
+	err.SetLocation(1)
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	stderrors "errors"
+	"io/fs"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type maskSuite struct{}
+
+var _ = gc.Suite(&maskSuite{})
+
+func (*maskSuite) TestMaskPreservingNil(c *gc.C) {
+	c.Assert(errors.MaskPreserving(nil, fs.ErrNotExist), gc.IsNil)
+}
+
+func (*maskSuite) TestMaskfPreservingNil(c *gc.C) {
+	c.Assert(errors.MaskfPreserving(nil, "masked"), gc.IsNil)
+}
+
+// anyError is a stand-in target type for errors.As that every error
+// implements, so the table test below can call As without depending on any
+// of the unexported typed-error types in allErrors. Because the masked
+// error itself implements it, errors.As matches the masked error first and
+// does not need to consult the hidden chain.
+type anyError interface {
+	Error() string
+}
+
+func (*maskSuite) TestMaskPreservingHidesCause(c *gc.C) {
+	for _, errInfo := range allErrors {
+		sentinel := errInfo.argsConstructor("foo", 1)
+		masked := errors.MaskPreserving(sentinel, sentinel)
+
+		c.Check(masked.Error(), gc.Equals, sentinel.Error())
+		c.Check(errors.Cause(masked), gc.Equals, masked)
+		c.Check(stderrors.Is(masked, sentinel), gc.Equals, true)
+
+		var target anyError
+		c.Check(stderrors.As(masked, &target), gc.Equals, true)
+
+		other := errInfo.argsConstructor("bar", 2)
+		c.Check(stderrors.Is(masked, other), gc.Equals, false)
+	}
+}
+
+func (*maskSuite) TestMaskPreservingFsSentinels(c *gc.C) {
+	masked := errors.MaskPreserving(fs.ErrNotExist, fs.ErrNotExist)
+
+	c.Assert(errors.Cause(masked), gc.Equals, masked)
+	c.Assert(stderrors.Is(masked, fs.ErrNotExist), gc.Equals, true)
+	c.Assert(stderrors.Is(masked, fs.ErrPermission), gc.Equals, false)
+}
+
+func (*maskSuite) TestMaskPreservingNoSentinelsTraversesEverything(c *gc.C) {
+	masked := errors.MaskPreserving(fs.ErrPermission)
+
+	c.Assert(stderrors.Is(masked, fs.ErrPermission), gc.Equals, true)
+}
+
+func (*maskSuite) TestMaskfPreservingTraversesEverything(c *gc.C) {
+	masked := errors.MaskfPreserving(fs.ErrNotExist, "removing %s", "/tmp/gone")
+
+	c.Assert(masked.Error(), gc.Equals, "removing /tmp/gone: file does not exist")
+	c.Assert(errors.Cause(masked), gc.Equals, masked)
+	c.Assert(stderrors.Is(masked, fs.ErrNotExist), gc.Equals, true)
+}
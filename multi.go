@@ -0,0 +1,192 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiCauseStrategy controls what (*Multi).Cause returns.
+type MultiCauseStrategy int
+
+const (
+	// MultiCauseFirst, the default, makes Cause return the cause of the
+	// first error appended to the Multi.
+	MultiCauseFirst MultiCauseStrategy = iota
+
+	// MultiCauseLast makes Cause return the cause of the last error
+	// appended to the Multi.
+	MultiCauseLast
+
+	// MultiCauseSelf makes Cause return the Multi itself, so
+	// errors.Cause does not descend into any of the wrapped errors.
+	MultiCauseSelf
+)
+
+// Multi aggregates zero or more errors collected during batch work into a
+// single error. A *Multi implements interface{ Unwrap() []error }, so the
+// standard library's errors.Is, errors.As and errors.Join all see through
+// it to the wrapped errors.
+//
+// Use NewMulti to create one:
+//
+//	multi := errors.NewMulti()
+//	for _, item := range items {
+//	    if err := process(item); err != nil {
+//	        multi.AppendWithContext(err, "processing %s", item)
+//	    }
+//	}
+//	return multi.ErrorOrNil()
+type Multi struct {
+	Err
+
+	errs     []error
+	strategy MultiCauseStrategy
+}
+
+// NewMulti returns a new, empty Multi with its location set to the call
+// site of NewMulti.
+func NewMulti() *Multi {
+	m := &Multi{}
+	m.SetLocation(1)
+	return m
+}
+
+// Append adds err to m. A nil err is ignored.
+func (m *Multi) Append(err error) {
+	if err == nil {
+		return
+	}
+	m.errs = append(m.errs, err)
+}
+
+// AppendWithContext annotates err with the given format string and
+// arguments (as Annotatef does) before adding it to m. A nil err is
+// ignored.
+func (m *Multi) AppendWithContext(err error, format string, args ...interface{}) {
+	if err == nil {
+		return
+	}
+	m.errs = append(m.errs, Annotatef(err, format, args...))
+}
+
+// SetCauseStrategy controls what Cause returns for m; see
+// MultiCauseStrategy.
+func (m *Multi) SetCauseStrategy(strategy MultiCauseStrategy) {
+	m.strategy = strategy
+}
+
+// ErrorOrNil returns m as an error if it has collected any errors, or nil
+// otherwise. This is the usual way to turn a Multi back into a plain
+// error to return from a function.
+func (m *Multi) ErrorOrNil() error {
+	if m == nil || len(m.errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Error implements error, joining the messages of every collected error.
+func (m *Multi) Error() string {
+	if len(m.errs) == 1 {
+		return m.errs[0].Error()
+	}
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: [%s]", len(m.errs), strings.Join(msgs, "; "))
+}
+
+// Cause is part of the causer interface. It returns the cause of the
+// first, last, or none of the wrapped errors, depending on the
+// MultiCauseStrategy set with SetCauseStrategy (the default is
+// MultiCauseFirst).
+func (m *Multi) Cause() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	switch m.strategy {
+	case MultiCauseLast:
+		return Cause(m.errs[len(m.errs)-1])
+	case MultiCauseSelf:
+		return nil
+	default: // MultiCauseFirst
+		return Cause(m.errs[0])
+	}
+}
+
+// Unwrap exposes every collected error to the standard library's
+// errors.Is, errors.As and errors.Join.
+func (m *Multi) Unwrap() []error {
+	return m.errs
+}
+
+// StackTrace returns one line per error in m's annotation stack, in the
+// same format as ErrorStack.
+func (m *Multi) StackTrace() []string {
+	return errorStack(m)
+}
+
+// multiErrorStack returns m's contribution to ErrorStack: a header giving
+// its location and error count, followed by each child's own stack,
+// indented and tagged with its index.
+func multiErrorStack(m *Multi) []string {
+	var header string
+	if file, line := m.Location(); file != "" {
+		header = fmt.Sprintf("%s:%d: %d error(s) occurred:", file, line, len(m.errs))
+	} else {
+		header = fmt.Sprintf("%d error(s) occurred:", len(m.errs))
+	}
+
+	lines := []string{header}
+	for i, err := range m.errs {
+		for _, line := range errorStack(err) {
+			lines = append(lines, fmt.Sprintf("  [%d] %s", i, line))
+		}
+	}
+	return lines
+}
+
+// isAllMulti reports whether err is a multi-error (anything satisfying
+// interface{ Unwrap() []error }) whose every child satisfies is, and
+// whether err was a multi-error at all. Typed-error satisfiers such as
+// IsNotFound use this so that, for a Multi, they require every collected
+// error to match rather than silently picking whichever child
+// Cause happens to resolve to.
+func isAllMulti(err error, is func(error) bool) (all, isMulti bool) {
+	u, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return false, false
+	}
+	children := u.Unwrap()
+	if len(children) == 0 {
+		return false, true
+	}
+	for _, child := range children {
+		if !is(child) {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// Split inverts a multi-error, returning the errors it wraps. If err is a
+// *Multi, Split returns its collected errors. If err implements
+// interface{ Unwrap() []error } some other way, Split returns the result
+// of calling it. Otherwise Split returns a single-element slice
+// containing err, or nil if err is nil.
+func Split(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if m, ok := err.(*Multi); ok {
+		return append([]error(nil), m.errs...)
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return u.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !juju_errors_nolocation
+
+package errors_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+func (*multiSuite) TestErrorStackIndentsChildren(c *gc.C) {
+	multi := errors.NewMulti()
+	loc := errorLocationValue(c)
+	first := errors.New("first")
+	loc1 := errorLocationValue(c)
+	second := errors.New("second")
+	loc2 := errorLocationValue(c)
+	multi.Append(first)
+	multi.Append(second)
+
+	c.Assert(errors.ErrorStack(multi.ErrorOrNil()), gc.Equals,
+		loc+": 2 error(s) occurred:\n  [0] "+loc1+": first\n  [1] "+loc2+": second")
+}
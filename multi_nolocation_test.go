@@ -0,0 +1,23 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build juju_errors_nolocation
+
+package errors_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+func (*multiSuite) TestErrorStackIndentsChildren(c *gc.C) {
+	multi := errors.NewMulti()
+	first := errors.New("first")
+	second := errors.New("second")
+	multi.Append(first)
+	multi.Append(second)
+
+	c.Assert(errors.ErrorStack(multi.ErrorOrNil()), gc.Equals,
+		"2 error(s) occurred:\n  [0] first\n  [1] second")
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	stderrors "errors"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type multiSuite struct{}
+
+var _ = gc.Suite(&multiSuite{})
+
+func (*multiSuite) TestEmptyMultiErrorOrNilIsNil(c *gc.C) {
+	multi := errors.NewMulti()
+	c.Assert(multi.ErrorOrNil(), gc.IsNil)
+}
+
+func (*multiSuite) TestAppendNilIgnored(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(nil)
+	c.Assert(multi.ErrorOrNil(), gc.IsNil)
+}
+
+func (*multiSuite) TestSingleErrorMessage(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(errors.New("boom"))
+	c.Assert(multi.ErrorOrNil().Error(), gc.Equals, "boom")
+}
+
+func (*multiSuite) TestMultipleErrorsMessage(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(errors.New("first"))
+	multi.Append(errors.New("second"))
+	c.Assert(multi.ErrorOrNil().Error(), gc.Equals, "2 errors occurred: [first; second]")
+}
+
+func (*multiSuite) TestAppendWithContext(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.AppendWithContext(errors.New("boom"), "processing %s", "widget")
+	c.Assert(multi.ErrorOrNil().Error(), gc.Equals, "processing widget: boom")
+}
+
+func (*multiSuite) TestUnwrapCompatibleWithErrorsJoin(c *gc.C) {
+	sentinel1 := stderrors.New("sentinel one")
+	sentinel2 := stderrors.New("sentinel two")
+
+	multi := errors.NewMulti()
+	multi.Append(sentinel1)
+	multi.Append(sentinel2)
+	err := multi.ErrorOrNil()
+
+	c.Assert(stderrors.Is(err, sentinel1), gc.Equals, true)
+	c.Assert(stderrors.Is(err, sentinel2), gc.Equals, true)
+	c.Assert(stderrors.Is(err, stderrors.New("sentinel three")), gc.Equals, false)
+}
+
+func (*multiSuite) TestCauseDefaultsToFirst(c *gc.C) {
+	first := stderrors.New("first cause")
+	second := stderrors.New("second cause")
+
+	multi := errors.NewMulti()
+	multi.Append(errors.Wrap(errors.New("oops"), first))
+	multi.Append(errors.Wrap(errors.New("oops"), second))
+
+	c.Assert(errors.Cause(multi.ErrorOrNil()), gc.Equals, first)
+}
+
+func (*multiSuite) TestCauseStrategyLast(c *gc.C) {
+	first := stderrors.New("first cause")
+	second := stderrors.New("second cause")
+
+	multi := errors.NewMulti()
+	multi.Append(errors.Wrap(errors.New("oops"), first))
+	multi.Append(errors.Wrap(errors.New("oops"), second))
+	multi.SetCauseStrategy(errors.MultiCauseLast)
+
+	c.Assert(errors.Cause(multi.ErrorOrNil()), gc.Equals, second)
+}
+
+func (*multiSuite) TestCauseStrategySelf(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(errors.Wrap(errors.New("oops"), stderrors.New("cause")))
+	multi.SetCauseStrategy(errors.MultiCauseSelf)
+
+	c.Assert(errors.Cause(multi.ErrorOrNil()), gc.Equals, multi.ErrorOrNil())
+}
+
+func (*multiSuite) TestIsNotFoundOnMultiOfNotFounds(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(errors.NotFoundf("widget"))
+	multi.Append(errors.NotFoundf("gadget"))
+
+	c.Assert(errors.IsNotFound(multi.ErrorOrNil()), gc.Equals, true)
+}
+
+func (*multiSuite) TestIsNotFoundOnMixedMultiIsFalseRegardlessOfOrder(c *gc.C) {
+	widgetFirst := errors.NewMulti()
+	widgetFirst.Append(errors.NotFoundf("widget"))
+	widgetFirst.Append(errors.AlreadyExistsf("gadget"))
+
+	gadgetFirst := errors.NewMulti()
+	gadgetFirst.Append(errors.AlreadyExistsf("gadget"))
+	gadgetFirst.Append(errors.NotFoundf("widget"))
+
+	c.Assert(errors.IsNotFound(widgetFirst.ErrorOrNil()), gc.Equals, false)
+	c.Assert(errors.IsNotFound(gadgetFirst.ErrorOrNil()), gc.Equals, false)
+	c.Assert(errors.IsAlreadyExists(widgetFirst.ErrorOrNil()), gc.Equals, false)
+	c.Assert(errors.IsAlreadyExists(gadgetFirst.ErrorOrNil()), gc.Equals, false)
+}
+
+func (*multiSuite) TestDetailsWalksIntoChildren(c *gc.C) {
+	multi := errors.NewMulti()
+	multi.Append(errors.New("first"))
+	multi.Append(errors.New("second"))
+
+	c.Assert(errors.Details(multi.ErrorOrNil()), Contains, "first")
+	c.Assert(errors.Details(multi.ErrorOrNil()), Contains, "second")
+}
+
+func (*multiSuite) TestSplitReturnsCollectedErrors(c *gc.C) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	multi := errors.NewMulti()
+	multi.Append(first)
+	multi.Append(second)
+
+	split := errors.Split(multi.ErrorOrNil())
+	c.Assert(split, gc.DeepEquals, []error{first, second})
+}
+
+func (*multiSuite) TestSplitOnNonMultiReturnsSingleElementSlice(c *gc.C) {
+	err := errors.New("boom")
+	c.Assert(errors.Split(err), gc.DeepEquals, []error{err})
+}
+
+func (*multiSuite) TestSplitOnNilReturnsNil(c *gc.C) {
+	c.Assert(errors.Split(nil), gc.IsNil)
+}
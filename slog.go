@@ -0,0 +1,39 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, so log/slog handlers emit e's
+// message, cause, typed-error tag and attached code as structured
+// attributes, alongside its full ErrorStack, instead of collapsing it to
+// one flat string.
+//
+// type and code are resolved by walking e's whole annotation stack with
+// typeTagOf and CodeOf (the latter also used by HTTPStatus, GRPCStatus
+// and Message), so they survive Annotate/Trace/Wrap instead of only being
+// reported when e itself is the typed or coded error. cause is omitted
+// when e has no distinct cause; type and code are omitted when no frame
+// in the stack carries one.
+func (e *Err) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs, slog.String("msg", e.Error()))
+
+	if cause := Cause(e); cause != nil && cause != e {
+		attrs = append(attrs, slog.String("cause", cause.Error()))
+	}
+	if tag := typeTagOf(e); tag != "" {
+		attrs = append(attrs, slog.String("type", tag))
+	}
+	if scope, category, detail, ok := CodeOf(e); ok {
+		attrs = append(attrs, slog.Group("code",
+			slog.Uint64("scope", uint64(scope)),
+			slog.Uint64("category", uint64(category)),
+			slog.Uint64("detail", uint64(detail)),
+		))
+	}
+	attrs = append(attrs, slog.String("stack", ErrorStack(e)))
+
+	return slog.GroupValue(attrs...)
+}
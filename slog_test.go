@@ -0,0 +1,52 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/errors"
+)
+
+type slogSuite struct{}
+
+var _ = gc.Suite(&slogSuite{})
+
+func (*slogSuite) TestLogValueGroupsFields(c *gc.C) {
+	err := errors.WithCode(errors.NotFoundf("widget"), 1, errors.CategoryResource, 2)
+	err = errors.Annotate(err, "looking up order")
+
+	valuer, ok := err.(slog.LogValuer)
+	c.Assert(ok, gc.Equals, true)
+
+	group := valuer.LogValue().Group()
+	attrs := make(map[string]slog.Value)
+	for _, attr := range group {
+		attrs[attr.Key] = attr.Value
+	}
+	c.Assert(attrs["msg"].Kind(), gc.Equals, slog.KindString)
+	c.Assert(attrs["cause"].Kind(), gc.Equals, slog.KindString)
+	c.Assert(attrs["stack"].Kind(), gc.Equals, slog.KindString)
+
+	// type and code must survive the Annotate wrapping the WithCode'd
+	// NotFoundf is buried under, the same way CodeOf does.
+	c.Assert(attrs["type"].String(), gc.Equals, "notFound")
+	c.Assert(attrs["code"].Kind(), gc.Equals, slog.KindGroup)
+}
+
+func TestLogValueViaSlogHandler(t *testing.T) {
+	err := errors.NotFoundf("widget")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("lookup failed", "err", err)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"type":"notFound"`)) {
+		t.Fatalf("expected structured type field in log output, got %s", buf.String())
+	}
+}
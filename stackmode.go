@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import "sync/atomic"
+
+// Mode controls how (*Err).SetLocation records the location of an error.
+type Mode int32
+
+const (
+	// ModeLazy records only a program counter when an error is created,
+	// and resolves it to a file and line the first time Location (or
+	// anything that calls it, such as Details or ErrorStack) is used.
+	// This is the default: it avoids the cost of formatting a file:line
+	// string for errors that are never inspected.
+	ModeLazy Mode = iota
+
+	// ModeEager resolves and stores the file and line immediately, as
+	// this package always did before SetStackMode was introduced.
+	ModeEager
+
+	// ModeOff skips location recording entirely. Location always
+	// returns "", 0, and Details/ErrorStack print without file:line.
+	ModeOff
+)
+
+// mode holds the current Mode, stored as an int32 so SetStackMode can be
+// called concurrently with error creation.
+var mode int32 = int32(ModeLazy)
+
+// SetStackMode sets how errors created from this point on record their
+// location. It is typically called once, from an init function or early
+// in main, and is safe to call concurrently with error creation elsewhere.
+func SetStackMode(m Mode) {
+	atomic.StoreInt32(&mode, int32(m))
+}
+
+// stackMode returns the current Mode.
+func stackMode() Mode {
+	return Mode(atomic.LoadInt32(&mode))
+}
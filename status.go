@@ -0,0 +1,137 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package errors
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// categoryStatus describes the default HTTP status and gRPC code for a
+// Category attached via WithCode.
+type categoryStatus struct {
+	http int
+	grpc codes.Code
+}
+
+var categoryStatuses = map[Category]categoryStatus{
+	CategoryValidation: {http.StatusBadRequest, codes.InvalidArgument},
+	CategoryDatabase:   {http.StatusInternalServerError, codes.Internal},
+	CategoryResource:   {http.StatusConflict, codes.FailedPrecondition},
+	CategoryAuth:       {http.StatusUnauthorized, codes.Unauthenticated},
+	CategorySystem:     {http.StatusInternalServerError, codes.Internal},
+	CategoryPubSub:     {http.StatusInternalServerError, codes.Internal},
+}
+
+// typeStatus describes the default HTTP status and gRPC code for one of
+// the typed errors in errortypes.go.
+type typeStatus struct {
+	satisfier func(error) bool
+	http      int
+	grpc      codes.Code
+}
+
+// typeStatuses maps each typed error to its HTTP status and gRPC code. It
+// is checked in order, so more specific satisfiers should be listed before
+// more general ones.
+var typeStatuses = []typeStatus{
+	{IsNotFound, http.StatusNotFound, codes.NotFound},
+	{IsUserNotFound, http.StatusNotFound, codes.NotFound},
+	{IsUnauthorized, http.StatusUnauthorized, codes.Unauthenticated},
+	{IsAlreadyExists, http.StatusConflict, codes.AlreadyExists},
+	{IsTimeout, http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	{IsQuotaLimitExceeded, http.StatusTooManyRequests, codes.ResourceExhausted},
+	{IsNotImplemented, http.StatusNotImplemented, codes.Unimplemented},
+	{IsNotSupported, http.StatusNotImplemented, codes.Unimplemented},
+	{IsBadRequest, http.StatusBadRequest, codes.InvalidArgument},
+	{IsForbidden, http.StatusForbidden, codes.PermissionDenied},
+	{IsMethodNotAllowed, http.StatusMethodNotAllowed, codes.Unimplemented},
+	{IsNotYetAvailable, http.StatusServiceUnavailable, codes.Unavailable},
+	{IsNotValid, http.StatusUnprocessableEntity, codes.InvalidArgument},
+	{IsNotProvisioned, http.StatusConflict, codes.FailedPrecondition},
+	{IsNotAssigned, http.StatusConflict, codes.FailedPrecondition},
+}
+
+// lookupStatus resolves err to an HTTP status and gRPC code, preferring an
+// explicitly attached Code (see WithCode) over the 15 typed errors, and
+// falling back to a generic "unknown" status if neither applies.
+func lookupStatus(err error) (int, codes.Code) {
+	if _, category, detail, ok := CodeOf(err); ok {
+		if category == uint32(CategoryGRPC) {
+			return runtimeHTTPStatus(codes.Code(detail)), codes.Code(detail)
+		}
+		if cs, ok := categoryStatuses[Category(category)]; ok {
+			return cs.http, cs.grpc
+		}
+	}
+	for _, ts := range typeStatuses {
+		if ts.satisfier(err) {
+			return ts.http, ts.grpc
+		}
+	}
+	return http.StatusInternalServerError, codes.Unknown
+}
+
+// runtimeHTTPStatus gives a reasonable HTTP status for a raw gRPC code,
+// used when a Code's category is CategoryGRPC and its detail is itself a
+// gRPC code.
+func runtimeHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.Aborted:
+		return http.StatusConflict
+	case codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// HTTPStatus returns the HTTP status code that best matches err: the
+// status registered for err's Category if it carries one (see WithCode),
+// otherwise the status for whichever of the 15 typed errors err satisfies,
+// otherwise http.StatusInternalServerError.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	status, _ := lookupStatus(err)
+	return status
+}
+
+// GRPCStatus returns the gRPC status that best matches err, using the same
+// resolution order as HTTPStatus. The returned status's message is
+// err.Error().
+func GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	_, code := lookupStatus(err)
+	return status.New(code, err.Error())
+}